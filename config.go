@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target describes one service's release YAML to bump: which file and tag
+// key hold the commit hash, and which GitHub repo/owner that hash should be
+// read from.
+type Target struct {
+	YAMLFile string `yaml:"yamlFile"`
+	YAMLTag  string `yaml:"yamlTag"`
+	Repo     string `yaml:"repo"`
+	Owner    string `yaml:"owner"`
+}
+
+// Config is the -config release.yaml file describing every service to bump
+// together in a single pull request.
+type Config struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// loadConfig reads and parses a multi-service release config.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("config must declare at least one target")
+	}
+
+	for i, t := range cfg.Targets {
+		if t.YAMLFile == "" || t.YAMLTag == "" || t.Repo == "" || t.Owner == "" {
+			return nil, fmt.Errorf("target %d is missing yamlFile, yamlTag, repo or owner", i)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// bump is the resolved old/new commit hash pair for one target.
+type bump struct {
+	Target        Target
+	OldCommitHash string
+	NewCommitHash string
+}
+
+// releaseDigest returns a short, deterministic digest of the services and
+// commit hashes involved in a release, used to name the shared branch/PR so
+// that re-running with the same inputs reuses the same branch.
+func releaseDigest(bumps []bump) string {
+	parts := make([]string, len(bumps))
+	for i, b := range bumps {
+		parts[i] = fmt.Sprintf("%s@%s", b.Target.Repo, b.NewCommitHash)
+	}
+	sort.Strings(parts)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, ",")))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+// allUnchanged reports whether every bump's new commit hash matches its old
+// one, meaning a rerun would produce no YAML changes at all and the whole
+// release can be skipped before touching any branch or pull request.
+func allUnchanged(bumps []bump) bool {
+	for _, b := range bumps {
+		if b.NewCommitHash != b.OldCommitHash {
+			return false
+		}
+	}
+
+	return true
+}
+
+// repoNames returns the service repo for each bump, in order.
+func repoNames(bumps []bump) []string {
+	names := make([]string, len(bumps))
+	for i, b := range bumps {
+		names[i] = b.Target.Repo
+	}
+
+	return names
+}
+
+// pullRequestBody renders a per-service compare-URL table for the PR body.
+func pullRequestBody(bumps []bump) string {
+	var b strings.Builder
+
+	b.WriteString("| Service | Old..New | Compare |\n")
+	b.WriteString("|---|---|---|\n")
+	for _, bump := range bumps {
+		fmt.Fprintf(&b, "| %s | %s..%s | https://github.com/%s/%s/compare/%s...%s |\n",
+			bump.Target.Repo, bump.OldCommitHash, bump.NewCommitHash,
+			bump.Target.Owner, bump.Target.Repo, bump.OldCommitHash, bump.NewCommitHash)
+	}
+
+	return b.String()
+}
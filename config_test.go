@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func TestReleaseDigest(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []bump
+		equal bool
+	}{
+		{
+			name:  "same bumps in different order produce the same digest",
+			a:     []bump{{Target: Target{Repo: "api"}, NewCommitHash: "1a2b3c4d"}, {Target: Target{Repo: "web"}, NewCommitHash: "deadbeef"}},
+			b:     []bump{{Target: Target{Repo: "web"}, NewCommitHash: "deadbeef"}, {Target: Target{Repo: "api"}, NewCommitHash: "1a2b3c4d"}},
+			equal: true,
+		},
+		{
+			name:  "a changed commit hash produces a different digest",
+			a:     []bump{{Target: Target{Repo: "api"}, NewCommitHash: "1a2b3c4d"}},
+			b:     []bump{{Target: Target{Repo: "api"}, NewCommitHash: "deadbeef"}},
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := releaseDigest(tt.a) == releaseDigest(tt.b)
+			if got != tt.equal {
+				t.Fatalf("releaseDigest(a) == releaseDigest(b) = %v, want %v", got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestAllUnchanged(t *testing.T) {
+	tests := []struct {
+		name  string
+		bumps []bump
+		want  bool
+	}{
+		{
+			name:  "no bumps",
+			bumps: nil,
+			want:  true,
+		},
+		{
+			name:  "single bump unchanged",
+			bumps: []bump{{OldCommitHash: "1a2b3c4d", NewCommitHash: "1a2b3c4d"}},
+			want:  true,
+		},
+		{
+			name:  "single bump changed",
+			bumps: []bump{{OldCommitHash: "1a2b3c4d", NewCommitHash: "deadbeef"}},
+			want:  false,
+		},
+		{
+			name: "one of several bumps changed",
+			bumps: []bump{
+				{OldCommitHash: "1a2b3c4d", NewCommitHash: "1a2b3c4d"},
+				{OldCommitHash: "cafef00d", NewCommitHash: "deadbeef"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allUnchanged(tt.bumps); got != tt.want {
+				t.Fatalf("allUnchanged(%+v) = %v, want %v", tt.bumps, got, tt.want)
+			}
+		})
+	}
+}
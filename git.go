@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// openRepo opens the git repository rooted at the current working
+// directory.
+func openRepo() (*git.Repository, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, fmt.Errorf("open repository: %w", err)
+	}
+
+	return repo, nil
+}
+
+// createOrCheckoutBranchGoGit creates branch from the current HEAD if it
+// doesn't exist yet, or checks it out if it does. This is the default,
+// in-process replacement for shelling out to git checkout.
+func createOrCheckoutBranchGoGit(branch string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("get worktree: %w", err)
+	}
+
+	ref := plumbing.NewBranchReferenceName(branch)
+
+	if _, err := repo.Reference(ref, false); err == nil {
+		if err := wt.Checkout(&git.CheckoutOptions{Branch: ref}); err != nil {
+			return fmt.Errorf("checkout %s: %w", branch, err)
+		}
+
+		return nil
+	}
+
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: ref, Create: true}); err != nil {
+		return fmt.Errorf("create branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+// gitCommitGoGit stages filePath and commits it, using GITHUB_USERNAME /
+// GITHUB_EMAIL as the author identity. If staging filePath leaves the
+// worktree clean, the commit is skipped, which happens on a rerun where
+// updateYamlFile already wrote the value the target tag holds.
+func gitCommitGoGit(filePath, commitMessage string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("get worktree: %w", err)
+	}
+
+	if _, err := wt.Add(filePath); err != nil {
+		return fmt.Errorf("add %s: %w", filePath, err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return fmt.Errorf("get worktree status: %w", err)
+	}
+	if status.IsClean() {
+		return nil
+	}
+
+	author := &object.Signature{
+		Name:  os.Getenv("GITHUB_USERNAME"),
+		Email: os.Getenv("GITHUB_EMAIL"),
+		When:  time.Now(),
+	}
+
+	if _, err := wt.Commit(commitMessage, &git.CommitOptions{Author: author}); err != nil {
+		return fmt.Errorf("commit %q: %w", commitMessage, err)
+	}
+
+	return nil
+}
+
+// gitPushGoGit pushes branch to origin, authenticating with token as an
+// HTTP basic auth password.
+func gitPushGoGit(branch, token string) error {
+	repo, err := openRepo()
+	if err != nil {
+		return err
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch))
+
+	err = repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth: &githttp.BasicAuth{
+			Username: "x-access-token",
+			Password: token,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("push %s: %w", branch, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// initRepoWithCommit creates a git repo in a temp directory with one commit
+// adding release.yaml, and chdirs the test process into it since openRepo
+// always opens ".".
+func initRepoWithCommit(t *testing.T) (*git.Repository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	filePath := filepath.Join(dir, "release.yaml")
+	if err := os.WriteFile(filePath, []byte("tag: master_1a2b3c4d\n"), 0644); err != nil {
+		t.Fatalf("write release.yaml: %v", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("get worktree: %v", err)
+	}
+	if _, err := wt.Add("release.yaml"); err != nil {
+		t.Fatalf("add release.yaml: %v", err)
+	}
+
+	sig := &object.Signature{Name: "tester", Email: "tester@example.com", When: time.Now()}
+	if _, err := wt.Commit("initial", &git.CommitOptions{Author: sig}); err != nil {
+		t.Fatalf("initial commit: %v", err)
+	}
+
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir %s: %v", dir, err)
+	}
+	t.Cleanup(func() { os.Chdir(orig) })
+
+	return repo, dir
+}
+
+func commitCount(t *testing.T, repo *git.Repository) int {
+	t.Helper()
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+
+	n := 0
+	if err := commits.ForEach(func(*object.Commit) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatalf("walk log: %v", err)
+	}
+
+	return n
+}
+
+func TestCreateOrCheckoutBranchGoGit(t *testing.T) {
+	repo, _ := initRepoWithCommit(t)
+
+	initialHead, err := repo.Head()
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+	initialBranch := initialHead.Name()
+
+	if err := createOrCheckoutBranchGoGit("release_test"); err != nil {
+		t.Fatalf("create branch: %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+	if want := plumbing.NewBranchReferenceName("release_test"); head.Name() != want {
+		t.Fatalf("head = %s, want %s", head.Name(), want)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("get worktree: %v", err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: initialBranch}); err != nil {
+		t.Fatalf("checkout back to %s: %v", initialBranch, err)
+	}
+
+	// release_test already exists now, so this call should check it out
+	// rather than trying (and failing) to create it again.
+	if err := createOrCheckoutBranchGoGit("release_test"); err != nil {
+		t.Fatalf("checkout existing branch: %v", err)
+	}
+
+	head, err = repo.Head()
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+	if want := plumbing.NewBranchReferenceName("release_test"); head.Name() != want {
+		t.Fatalf("head = %s, want %s", head.Name(), want)
+	}
+}
+
+func TestGitCommitGoGitSkipsNoOpCommit(t *testing.T) {
+	repo, dir := initRepoWithCommit(t)
+	t.Setenv("GITHUB_USERNAME", "tester")
+	t.Setenv("GITHUB_EMAIL", "tester@example.com")
+
+	before := commitCount(t, repo)
+
+	// Rewriting the same content is a no-op: gitCommitGoGit must skip the
+	// commit, not fail trying to commit a clean worktree.
+	filePath := filepath.Join(dir, "release.yaml")
+	if err := os.WriteFile(filePath, []byte("tag: master_1a2b3c4d\n"), 0644); err != nil {
+		t.Fatalf("rewrite release.yaml: %v", err)
+	}
+	if err := gitCommitGoGit("release.yaml", "noop bump"); err != nil {
+		t.Fatalf("gitCommitGoGit (no-op): %v", err)
+	}
+	if got := commitCount(t, repo); got != before {
+		t.Fatalf("commit count = %d, want %d (no-op commit should be skipped)", got, before)
+	}
+
+	// An actual change should still produce a new commit.
+	if err := os.WriteFile(filePath, []byte("tag: master_deadbeef\n"), 0644); err != nil {
+		t.Fatalf("bump release.yaml: %v", err)
+	}
+	if err := gitCommitGoGit("release.yaml", "real bump"); err != nil {
+		t.Fatalf("gitCommitGoGit (change): %v", err)
+	}
+	if got := commitCount(t, repo); got != before+1 {
+		t.Fatalf("commit count = %d, want %d", got, before+1)
+	}
+}
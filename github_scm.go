@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v65/github"
+	"golang.org/x/oauth2"
+)
+
+// GitHubSCM implements SCM against the GitHub REST/Git Data API.
+type GitHubSCM struct {
+	client *github.Client
+	owner  string
+}
+
+func (g *GitHubSCM) LatestCommit(ctx context.Context, owner, repo, branch string) (string, error) {
+	ref, _, err := g.client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+	if err != nil {
+		return "", fmt.Errorf("error getting ref: %w", err)
+	}
+
+	return ref.GetObject().GetSHA()[:8], nil
+}
+
+// OpenPullRequest creates a pull request for head, or, if one is already
+// open for that branch (a rerun after a prior, still-unmerged release),
+// returns the existing PR's URL instead of failing.
+func (g *GitHubSCM) OpenPullRequest(ctx context.Context, head, base, title, body string) (string, error) {
+	pr := &github.NewPullRequest{
+		Title: &title,
+		Body:  &body,
+		Head:  &head,
+		Base:  &base,
+	}
+
+	createdPR, resp, err := g.client.PullRequests.Create(ctx, g.owner, devopsRepo, pr)
+	if err != nil {
+		if url, ok := g.existingPullRequestURL(ctx, resp, err, head); ok {
+			return url, nil
+		}
+
+		return "", fmt.Errorf("create pull request: %w", err)
+	}
+
+	return createdPR.GetHTMLURL(), nil
+}
+
+// existingPullRequestURL checks whether err is GitHub's 422 "A pull request
+// already exists" response and, if so, looks the open PR up by head branch
+// so a rerun for an already-open release can reuse it instead of failing.
+func (g *GitHubSCM) existingPullRequestURL(ctx context.Context, resp *github.Response, err error, head string) (string, bool) {
+	if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		return "", false
+	}
+	if !strings.Contains(err.Error(), "A pull request already exists") {
+		return "", false
+	}
+
+	prs, _, err := g.client.PullRequests.List(ctx, g.owner, devopsRepo, &github.PullRequestListOptions{
+		Head:  g.owner + ":" + head,
+		State: "open",
+	})
+	if err != nil || len(prs) == 0 {
+		return "", false
+	}
+
+	return prs[0].GetHTMLURL(), true
+}
+
+// githubClient builds an authenticated *github.Client from a resolved
+// token.
+func githubClient(ctx context.Context, token string) *github.Client {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+
+	return github.NewClient(oauth2.NewClient(ctx, ts))
+}
+
+// githubTokenFromCLI shells out to the gh CLI's cached session, used as the
+// last resort when no -token flag or GITHUB_TOKEN env var is set.
+func githubTokenFromCLI() (string, error) {
+	out, err := runCommand("gh", "auth", "status", "--show-token")
+	if err != nil {
+		return "", fmt.Errorf("get token from gh cli client: %w", err)
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "Token:") {
+			parts := strings.Fields(line)
+			if len(parts) >= 2 {
+				return parts[2], nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("token not found in gh auth status output")
+}
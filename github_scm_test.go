@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v65/github"
+)
+
+func TestExistingPullRequestURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       *github.Response
+		err        error
+		listStatus int
+		listPRs    []*github.PullRequest
+		wantURL    string
+		wantOK     bool
+	}{
+		{
+			name:   "nil response",
+			resp:   nil,
+			err:    fmt.Errorf("some transport error"),
+			wantOK: false,
+		},
+		{
+			name:   "non-422 status",
+			resp:   &github.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}},
+			err:    fmt.Errorf("bad request"),
+			wantOK: false,
+		},
+		{
+			name:   "422 for a different reason",
+			resp:   &github.Response{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}},
+			err:    fmt.Errorf("Validation Failed"),
+			wantOK: false,
+		},
+		{
+			name:       "422 pull request already exists, list finds it",
+			resp:       &github.Response{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}},
+			err:        fmt.Errorf("POST https://api.github.com/repos/acme/devops/pulls: 422 A pull request already exists for acme:release_prod_abcd1234."),
+			listStatus: http.StatusOK,
+			listPRs:    []*github.PullRequest{{HTMLURL: github.String("https://github.com/acme/devops/pull/42")}},
+			wantURL:    "https://github.com/acme/devops/pull/42",
+			wantOK:     true,
+		},
+		{
+			name:       "422 pull request already exists, list returns no results",
+			resp:       &github.Response{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}},
+			err:        fmt.Errorf("422 A pull request already exists for acme:release_prod_abcd1234."),
+			listStatus: http.StatusOK,
+			listPRs:    nil,
+			wantOK:     false,
+		},
+		{
+			name:       "422 pull request already exists, list itself fails",
+			resp:       &github.Response{Response: &http.Response{StatusCode: http.StatusUnprocessableEntity}},
+			err:        fmt.Errorf("422 A pull request already exists for acme:release_prod_abcd1234."),
+			listStatus: http.StatusInternalServerError,
+			wantOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery url.Values
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotQuery = r.URL.Query()
+				w.WriteHeader(tt.listStatus)
+				if tt.listStatus == http.StatusOK {
+					_ = json.NewEncoder(w).Encode(tt.listPRs)
+				}
+			}))
+			defer server.Close()
+
+			client := github.NewClient(nil)
+			baseURL, err := url.Parse(server.URL + "/")
+			if err != nil {
+				t.Fatalf("parse server URL: %v", err)
+			}
+			client.BaseURL = baseURL
+
+			scm := &GitHubSCM{client: client, owner: "acme"}
+
+			gotURL, gotOK := scm.existingPullRequestURL(context.Background(), tt.resp, tt.err, "release_prod_abcd1234")
+			if gotOK != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", gotOK, tt.wantOK)
+			}
+			if gotURL != tt.wantURL {
+				t.Fatalf("url = %q, want %q", gotURL, tt.wantURL)
+			}
+
+			if tt.wantOK || (tt.resp != nil && tt.resp.StatusCode == http.StatusUnprocessableEntity && gotQuery != nil) {
+				if got := gotQuery.Get("head"); got != "acme:release_prod_abcd1234" {
+					t.Fatalf("head query param = %q, want %q", got, "acme:release_prod_abcd1234")
+				}
+				if got := gotQuery.Get("state"); got != "open" {
+					t.Fatalf("state query param = %q, want %q", got, "open")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+// GitLabSCM implements SCM against the GitLab REST API, for teams whose
+// devops repo lives on GitLab (or a self-hosted GitLab instance) instead of
+// GitHub.
+type GitLabSCM struct {
+	httpClient  *http.Client
+	baseURL     string
+	token       string
+	projectPath string // owner/devopsRepo, the project merge requests are opened against
+}
+
+func NewGitLabSCM(apiURL, owner, token string) (*GitLabSCM, error) {
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token is required")
+	}
+	if apiURL == "" {
+		apiURL = defaultGitLabAPIURL
+	}
+
+	return &GitLabSCM{
+		httpClient:  http.DefaultClient,
+		baseURL:     strings.TrimRight(apiURL, "/"),
+		token:       token,
+		projectPath: owner + "/" + devopsRepo,
+	}, nil
+}
+
+func (g *GitLabSCM) LatestCommit(ctx context.Context, owner, repo, branch string) (string, error) {
+	u := fmt.Sprintf("%s/projects/%s/repository/branches/%s", g.baseURL, url.PathEscape(owner+"/"+repo), url.PathEscape(branch))
+
+	var resp struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := g.do(ctx, http.MethodGet, u, nil, &resp); err != nil {
+		return "", fmt.Errorf("get branch %s: %w", branch, err)
+	}
+
+	return resp.Commit.ID[:8], nil
+}
+
+func (g *GitLabSCM) OpenPullRequest(ctx context.Context, head, base, title, body string) (string, error) {
+	u := fmt.Sprintf("%s/projects/%s/merge_requests", g.baseURL, url.PathEscape(g.projectPath))
+
+	payload := map[string]string{
+		"source_branch": head,
+		"target_branch": base,
+		"title":         title,
+		"description":   body,
+	}
+
+	var resp struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, u, payload, &resp); err != nil {
+		return "", fmt.Errorf("open merge request: %w", err)
+	}
+
+	return resp.WebURL, nil
+}
+
+func (g *GitLabSCM) do(ctx context.Context, method, rawURL string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(data))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGitLabSCMLatestCommit(t *testing.T) {
+	var gotPath, gotToken string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"commit": map[string]string{"id": "1a2b3c4d5e6f"},
+		})
+	}))
+	defer server.Close()
+
+	scm, err := NewGitLabSCM(server.URL, "my/org", "tok3n")
+	if err != nil {
+		t.Fatalf("NewGitLabSCM: %v", err)
+	}
+
+	hash, err := scm.LatestCommit(context.Background(), "my/org", "svc repo", "feature/needs escaping")
+	if err != nil {
+		t.Fatalf("LatestCommit: %v", err)
+	}
+	if hash != "1a2b3c4d" {
+		t.Fatalf("hash = %q, want %q", hash, "1a2b3c4d")
+	}
+
+	wantPath := "/projects/" + url.PathEscape("my/org/svc repo") + "/repository/branches/" + url.PathEscape("feature/needs escaping")
+	if gotPath != wantPath {
+		t.Fatalf("request path = %q, want %q", gotPath, wantPath)
+	}
+	if gotToken != "tok3n" {
+		t.Fatalf("PRIVATE-TOKEN header = %q, want %q", gotToken, "tok3n")
+	}
+}
+
+func TestGitLabSCMOpenPullRequest(t *testing.T) {
+	var gotPath, gotToken, gotMethod string
+	var gotBody map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		gotMethod = r.Method
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"web_url": "https://gitlab.example.com/my/devops/-/merge_requests/7",
+		})
+	}))
+	defer server.Close()
+
+	scm, err := NewGitLabSCM(server.URL, "my", "tok3n")
+	if err != nil {
+		t.Fatalf("NewGitLabSCM: %v", err)
+	}
+
+	url, err := scm.OpenPullRequest(context.Background(), "release_prod_abcd1234", "master", "Release PROD", "body")
+	if err != nil {
+		t.Fatalf("OpenPullRequest: %v", err)
+	}
+	if url != "https://gitlab.example.com/my/devops/-/merge_requests/7" {
+		t.Fatalf("url = %q, want the fake server's web_url", url)
+	}
+
+	wantPath := "/projects/my%2F" + devopsRepo + "/merge_requests"
+	if gotPath != wantPath {
+		t.Fatalf("request path = %q, want %q", gotPath, wantPath)
+	}
+	if gotMethod != http.MethodPost {
+		t.Fatalf("method = %q, want POST", gotMethod)
+	}
+	if gotToken != "tok3n" {
+		t.Fatalf("PRIVATE-TOKEN header = %q, want %q", gotToken, "tok3n")
+	}
+
+	wantBody := map[string]string{
+		"source_branch": "release_prod_abcd1234",
+		"target_branch": "master",
+		"title":         "Release PROD",
+		"description":   "body",
+	}
+	for k, v := range wantBody {
+		if gotBody[k] != v {
+			t.Fatalf("request body[%q] = %q, want %q", k, gotBody[k], v)
+		}
+	}
+}
+
+func TestGitLabSCMErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"branch not found"}`))
+	}))
+	defer server.Close()
+
+	scm, err := NewGitLabSCM(server.URL, "my", "tok3n")
+	if err != nil {
+		t.Fatalf("NewGitLabSCM: %v", err)
+	}
+
+	_, err = scm.LatestCommit(context.Background(), "my", "repo", "master")
+	if err == nil {
+		t.Fatalf("LatestCommit: expected error for a >=300 status, got none")
+	}
+	if !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "branch not found") {
+		t.Fatalf("LatestCommit error = %q, want it to mention the status and body", err)
+	}
+}
+
+func TestNewGitLabSCM(t *testing.T) {
+	if _, err := NewGitLabSCM("", "owner", ""); err == nil {
+		t.Fatalf("NewGitLabSCM: expected error when token is empty")
+	}
+
+	scm, err := NewGitLabSCM("", "owner", "tok3n")
+	if err != nil {
+		t.Fatalf("NewGitLabSCM: %v", err)
+	}
+	if scm.baseURL != defaultGitLabAPIURL {
+		t.Fatalf("baseURL = %q, want default %q", scm.baseURL, defaultGitLabAPIURL)
+	}
+}
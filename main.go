@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"flag"
@@ -9,22 +8,30 @@ import (
 	"log"
 	"os"
 	"os/exec"
-	"regexp"
 	"strings"
-
-	"github.com/google/go-github/v65/github"
-	"golang.org/x/oauth2"
 )
 
+// devopsRepo is the repository that holds the release YAML files and
+// receives the bump pull request.
+const devopsRepo = "devops"
+
 func main() {
-	newCommitHashFlag := flag.String("c", "", "8-character commit hash")
+	newCommitHashFlag := flag.String("c", "", "8-character commit hash (single-target mode only)")
 	owner := flag.String("o", "", "GitHub owner")
-	repo := flag.String("r", "", "GitHub repository")
+	repo := flag.String("r", "", "GitHub repository (single-target mode)")
 	env := flag.String("e", "", "Environment (dev or prod)")
-	yamlFile := flag.String("f", "", "YAML File")
-	yamlTag := flag.String("t", "", "YAML Tag")
+	yamlFile := flag.String("f", "", "YAML File (single-target mode)")
+	yamlTag := flag.String("t", "", "YAML Tag (single-target mode)")
+	configPath := flag.String("config", "", "Path to a release.yaml listing multiple services to bump in one PR")
 
 	master := flag.String("m", "master", "Name of main branch")
+	sign := flag.Bool("sign", false, "Create the commit(s) via the GitHub Git Data API, signed with GPG_SECRET_KEY")
+
+	scmName := flag.String("scm", "github", "SCM backend: github or gitlab")
+	apiURL := flag.String("api-url", "", "Base API URL for a self-hosted SCM instance (GitLab only)")
+	token := flag.String("token", "", "SCM API token (falls back to GITHUB_TOKEN/GITLAB_TOKEN, then gh auth status for GitHub)")
+
+	useShellGit := flag.Bool("use-shell-git", false, "Shell out to the git CLI instead of using go-git in-process")
 
 	flag.Parse()
 
@@ -36,69 +43,114 @@ func main() {
 		log.Fatal("Organization (-o) is required")
 	}
 
-	if *repo == "" {
-		log.Fatal("Repository (-r) is required")
+	targets, err := resolveTargets(*configPath, *repo, *owner, *yamlFile, *yamlTag)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	ghToken, err := runCommand("gh", "auth", "status", "--show-token")
+	ctx := context.Background()
+
+	resolvedToken, err := resolveToken(*scmName, *token)
 	if err != nil {
-		log.Fatalf("Failed to get token from gh cli client: %v", err)
+		log.Fatal(err)
 	}
 
-	lines := strings.Split(ghToken, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "Token:") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				ghToken = parts[2]
-				break
-			}
-		}
+	scmClient, gh, err := newSCM(ctx, *scmName, *apiURL, *owner, resolvedToken)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: ghToken},
-	)
+	if *sign && gh == nil {
+		log.Fatal("-sign is only supported with -scm github")
+	}
 
-	ctx := context.Background()
-	gh := github.NewClient(oauth2.NewClient(ctx, ts))
+	var bumps []bump
+	for _, t := range targets {
+		newCommitHash := ""
+		if len(targets) == 1 && *newCommitHashFlag != "" {
+			newCommitHash = *newCommitHashFlag
+		} else {
+			newCommitHash, err = scmClient.LatestCommit(ctx, t.Owner, t.Repo, *master)
+			if err != nil {
+				log.Fatalf("Failed to extract newest master for %s: %v", t.Repo, err)
+			}
+		}
 
-	newCommitHash := *newCommitHashFlag
-	if newCommitHash == "" {
-		newCommitHash, err = extractNewCommitHash(ctx, gh, *master, *owner, *repo)
+		oldCommitHash, err := extractOldCommitHash(t.YAMLFile, *master, t.YAMLTag)
 		if err != nil {
-			log.Fatalf("Failed to extract newest master: %v", err)
+			log.Fatalf("Could not extract the release tag from %s: %v", t.YAMLFile, err)
 		}
-	}
 
-	oldCommitHash, err := extractOldCommitHash(*yamlFile, *master, *yamlTag)
-	if err != nil {
-		log.Fatalf("Could not extract the release tag from %s: %v", *yamlFile, err)
+		bumps = append(bumps, bump{Target: t, OldCommitHash: oldCommitHash, NewCommitHash: newCommitHash})
 	}
 
-	branchName := fmt.Sprintf("%s_%s_%s", *repo, *env, newCommitHash)
-	if err := createOrCheckoutBranch(branchName); err != nil {
-		log.Fatalf("Failed to create or checkout branch: %v", err)
+	if allUnchanged(bumps) {
+		fmt.Println("Nothing to do: no commit hash changed since the last run")
+		return
 	}
 
-	// Update the YAML file
-	if err := updateYamlFile(*yamlFile, *master, *yamlTag, oldCommitHash, newCommitHash); err != nil {
-		log.Fatalf("Failed to update YAML file: %v", err)
-	}
+	branchName := fmt.Sprintf("release_%s_%s", *env, releaseDigest(bumps))
 
-	// Commit the changes
-	if err := gitCommit(*yamlFile, *env, *repo, newCommitHash); err != nil {
-		log.Fatalf("Failed to commit changes: %v", err)
-	}
+	if *sign {
+		signer, err := loadSigner()
+		if err != nil {
+			log.Fatalf("Failed to load GPG signer: %v", err)
+		}
+
+		masterRef, _, err := gh.Git.GetRef(ctx, *owner, devopsRepo, "refs/heads/"+*master)
+		if err != nil {
+			log.Fatalf("Failed to get ref for %s: %v", *master, err)
+		}
+		parentSHA := masterRef.GetObject().GetSHA()
+
+		for _, b := range bumps {
+			if b.OldCommitHash == b.NewCommitHash {
+				continue
+			}
+
+			commitMessage := fmt.Sprintf("%s %s %s", b.Target.Repo, strings.ToUpper(*env), b.NewCommitHash)
+
+			sha, err := createSignedCommit(ctx, gh, signer, *owner, devopsRepo, *master, parentSHA, b.Target.YAMLFile, b.Target.YAMLTag, b.OldCommitHash, b.NewCommitHash, commitMessage)
+			if err != nil {
+				log.Fatalf("Failed to create signed commit for %s: %v", b.Target.Repo, err)
+			}
+			parentSHA = sha
+		}
+
+		if err := updateBranchRef(ctx, gh, *owner, devopsRepo, branchName, parentSHA); err != nil {
+			log.Fatalf("Failed to update branch ref: %v", err)
+		}
+	} else {
+		if *useShellGit {
+			if err := createOrCheckoutBranch(branchName); err != nil {
+				log.Fatalf("Failed to create or checkout branch: %v", err)
+			}
+		} else {
+			if err := createOrCheckoutBranchGoGit(branchName); err != nil {
+				log.Fatalf("Failed to create or checkout branch: %v", err)
+			}
+		}
 
-	// Push the branch
-	if err := gitPush(branchName); err != nil {
-		log.Fatalf("Failed to push branch: %v", err)
+		for _, b := range bumps {
+			if err := commitBump(b, *master, *env, *useShellGit); err != nil {
+				log.Fatalf("Failed to commit changes for %s: %v", b.Target.Repo, err)
+			}
+		}
+
+		if *useShellGit {
+			if err := gitPush(branchName); err != nil {
+				log.Fatalf("Failed to push branch: %v", err)
+			}
+		} else {
+			if err := gitPushGoGit(branchName, resolvedToken); err != nil {
+				log.Fatalf("Failed to push branch: %v", err)
+			}
+		}
 	}
 
-	title := fmt.Sprintf("%s %s %s", *repo, strings.ToUpper(*env), newCommitHash)
-	description := fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", *owner, *repo, oldCommitHash, newCommitHash)
-	pullRequestURL, err := createPullRequest(ctx, gh, *owner, branchName, *master, title, description)
+	title := fmt.Sprintf("Release %s: %s", strings.ToUpper(*env), strings.Join(repoNames(bumps), ", "))
+	description := pullRequestBody(bumps)
+	pullRequestURL, err := scmClient.OpenPullRequest(ctx, branchName, *master, title, description)
 	if err != nil {
 		log.Fatalf("Failed to create pull request: %v", err)
 	}
@@ -106,36 +158,40 @@ func main() {
 	fmt.Printf("Pull request created successfully\nURL: %s\nTitle: %s\nDescription:\n%s\n", pullRequestURL, title, description)
 }
 
-func extractNewCommitHash(ctx context.Context, gh *github.Client, master, owner, repository string) (string, error) {
-	ref, _, err := gh.Git.GetRef(ctx, owner, repository, "refs/heads/"+master)
-	if err != nil {
-		return "", fmt.Errorf("error getting ref: %w", err)
+// resolveTargets returns the list of services to bump, either from a
+// -config file or, for backwards compatibility, from a single -r/-f/-t
+// flag triple.
+func resolveTargets(configPath, repo, owner, yamlFile, yamlTag string) ([]Target, error) {
+	if configPath != "" {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("load config: %w", err)
+		}
+
+		return cfg.Targets, nil
+	}
+
+	if repo == "" || yamlFile == "" || yamlTag == "" {
+		return nil, fmt.Errorf("either -config, or -r/-f/-t together, are required")
 	}
 
-	return ref.GetObject().GetSHA()[:8], nil
+	return []Target{{YAMLFile: yamlFile, YAMLTag: yamlTag, Repo: repo, Owner: owner}}, nil
 }
 
-func extractOldCommitHash(filePath, master, yamlTag string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("open file: %w", err)
+// commitBump updates a single target's YAML file and commits it, using
+// either go-git (default) or the git CLI (-use-shell-git).
+func commitBump(b bump, master, env string, useShellGit bool) error {
+	if err := updateYamlFile(b.Target.YAMLFile, master, b.Target.YAMLTag, b.OldCommitHash, b.NewCommitHash); err != nil {
+		return fmt.Errorf("update yaml file: %w", err)
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	re := regexp.MustCompile(master + `_(\w{8})`)
+	commitMessage := fmt.Sprintf("%s %s %s", b.Target.Repo, strings.ToUpper(env), b.NewCommitHash)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.Contains(line, yamlTag) {
-			match := re.FindStringSubmatch(line)
-			if len(match) > 1 {
-				return match[1], nil
-			}
-		}
+	if useShellGit {
+		return gitCommit(b.Target.YAMLFile, commitMessage)
 	}
 
-	return "", fmt.Errorf("commit hash not found")
+	return gitCommitGoGit(b.Target.YAMLFile, commitMessage)
 }
 
 func createOrCheckoutBranch(branch string) error {
@@ -155,30 +211,23 @@ func createOrCheckoutBranch(branch string) error {
 	return nil
 }
 
-func updateYamlFile(filePath, master, yamlTag, oldCommitHash, newCommitHash string) error {
-	input, err := os.ReadFile(filePath)
-	if err != nil {
-		return fmt.Errorf("read file: %w", err)
+// gitCommit stages and commits filePath, skipping the commit if staging it
+// left the worktree clean. That happens on a rerun where updateYamlFile
+// already wrote the value the target tag holds, e.g. because a previous run
+// of this tool committed and merged it already.
+func gitCommit(filePath, commitMessage string) error {
+	if _, err := runCommand("git", "add", filePath); err != nil {
+		return fmt.Errorf("run git add: %w", err)
 	}
 
-	output := strings.Replace(string(input),
-		fmt.Sprintf(`%s: "%s_%s"`, yamlTag, master, oldCommitHash),
-		fmt.Sprintf(`%s: "%s_%s"`, yamlTag, master, newCommitHash),
-		1)
-
-	if err := os.WriteFile(filePath, []byte(output), 0644); err != nil {
-		return fmt.Errorf("write file: %w", err)
+	status, err := runCommand("git", "status", "--porcelain", filePath)
+	if err != nil {
+		return fmt.Errorf("run git status: %w", err)
 	}
-
-	return nil
-}
-
-func gitCommit(filePath, env, repo, commitHash string) error {
-	if _, err := runCommand("git", "add", filePath); err != nil {
-		return fmt.Errorf("run git add: %w", err)
+	if strings.TrimSpace(status) == "" {
+		return nil
 	}
 
-	commitMessage := fmt.Sprintf("%s %s %s", repo, strings.ToUpper(env), commitHash)
 	if _, err := runCommand("git", "commit", "-m", commitMessage); err != nil {
 		return fmt.Errorf(`run git commit -m "%s": %w`, commitMessage, err)
 	}
@@ -194,22 +243,6 @@ func gitPush(branch string) error {
 	return nil
 }
 
-func createPullRequest(ctx context.Context, gh *github.Client, owner, branch, master, title, description string) (string, error) {
-	pr := &github.NewPullRequest{
-		Title: &title,
-		Body:  &description,
-		Head:  &branch,
-		Base:  &master,
-	}
-
-	createdPR, _, err := gh.PullRequests.Create(ctx, owner, "devops", pr)
-	if err != nil {
-		return "", fmt.Errorf("create pull request: %w", err)
-	}
-
-	return createdPR.GetHTMLURL(), nil
-}
-
 func runCommand(name string, args ...string) (string, error) {
 	var out bytes.Buffer
 
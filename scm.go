@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/go-github/v65/github"
+)
+
+// SCM abstracts the source-control host operations the release generator
+// needs: resolving a branch's latest commit and opening a pull/merge
+// request. GitHub and GitLab each implement it so the rest of the tool
+// doesn't care which host a release targets.
+type SCM interface {
+	LatestCommit(ctx context.Context, owner, repo, branch string) (string, error)
+	OpenPullRequest(ctx context.Context, head, base, title, body string) (string, error)
+}
+
+// resolveToken applies the credential fallback chain: an explicit flag,
+// then the backend's env var, then (GitHub only) the gh CLI's cached
+// session.
+func resolveToken(scmName, explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+
+	switch scmName {
+	case "github":
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			return token, nil
+		}
+
+		return githubTokenFromCLI()
+	case "gitlab":
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+			return token, nil
+		}
+
+		return "", fmt.Errorf("GITLAB_TOKEN is not set and -token was not provided")
+	default:
+		return "", fmt.Errorf("unknown -scm %q", scmName)
+	}
+}
+
+// newSCM builds the SCM backend selected by -scm. It also returns the
+// underlying *github.Client, non-nil only for the github backend, since
+// GPG-signed commits go through GitHub's Git Data API directly.
+func newSCM(ctx context.Context, scmName, apiURL, owner, token string) (SCM, *github.Client, error) {
+	switch scmName {
+	case "github":
+		client := githubClient(ctx, token)
+		return &GitHubSCM{client: client, owner: owner}, client, nil
+	case "gitlab":
+		scm, err := NewGitLabSCM(apiURL, owner, token)
+		return scm, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown -scm %q", scmName)
+	}
+}
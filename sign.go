@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v65/github"
+	"golang.org/x/crypto/openpgp"
+)
+
+// loadSigner reads an ASCII-armored private key from GPG_SECRET_KEY and
+// decrypts it with GPG_PASSPHRASE if it is encrypted. The returned entity is
+// used to produce detached signatures for commits created through the
+// GitHub Git Data API.
+func loadSigner() (*openpgp.Entity, error) {
+	armoredKey := os.Getenv("GPG_SECRET_KEY")
+	if armoredKey == "" {
+		return nil, fmt.Errorf("GPG_SECRET_KEY is not set")
+	}
+
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("read armored key ring: %w", err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("GPG_SECRET_KEY does not contain any keys")
+	}
+
+	signer := entities[0]
+	passphrase := []byte(os.Getenv("GPG_PASSPHRASE"))
+
+	if signer.PrivateKey != nil && signer.PrivateKey.Encrypted {
+		if err := signer.PrivateKey.Decrypt(passphrase); err != nil {
+			return nil, fmt.Errorf("decrypt private key: %w", err)
+		}
+	}
+	for _, subkey := range signer.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			if err := subkey.PrivateKey.Decrypt(passphrase); err != nil {
+				return nil, fmt.Errorf("decrypt subkey private key: %w", err)
+			}
+		}
+	}
+
+	return signer, nil
+}
+
+// messageSigner adapts an openpgp.Entity to github.MessageSigner so the
+// go-github client can ask for a detached, ASCII-armored signature over the
+// canonical commit object it builds internally.
+func messageSigner(signer *openpgp.Entity) github.MessageSignerFunc {
+	return func(w io.Writer, r io.Reader) error {
+		return openpgp.ArmoredDetachSign(w, signer, r, nil)
+	}
+}
+
+// createSignedCommit updates yamlFile on top of parentSHA through the
+// GitHub Git Data API, producing a GPG "Verified" commit instead of
+// shelling out to git commit. It returns the new commit's SHA; the caller
+// is responsible for pointing a branch ref at it (see updateBranchRef),
+// which lets multiple targets be chained into one branch.
+func createSignedCommit(ctx context.Context, gh *github.Client, signer *openpgp.Entity, owner, repo, master, parentSHA, yamlFile, yamlTag, oldCommitHash, newCommitHash, message string) (string, error) {
+	parentCommit, _, err := gh.Git.GetCommit(ctx, owner, repo, parentSHA)
+	if err != nil {
+		return "", fmt.Errorf("get commit %s: %w", parentSHA, err)
+	}
+
+	updated, err := renderUpdatedYAML(yamlFile, master, yamlTag, oldCommitHash, newCommitHash)
+	if err != nil {
+		return "", err
+	}
+
+	blob, _, err := gh.Git.CreateBlob(ctx, owner, repo, &github.Blob{
+		Content:  github.String(string(updated)),
+		Encoding: github.String("utf-8"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("create blob: %w", err)
+	}
+
+	tree, _, err := gh.Git.CreateTree(ctx, owner, repo, parentCommit.GetTree().GetSHA(), []*github.TreeEntry{
+		{
+			Path: github.String(yamlFile),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+			SHA:  blob.SHA,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("create tree: %w", err)
+	}
+
+	// CreateCommit signs the canonical commit text locally using this Date,
+	// then POSTs it to GitHub, which persists the same Date verbatim since
+	// it's set explicitly. Leaving Date nil would sign a zero-value
+	// timestamp while GitHub stamped the stored commit with the request
+	// time instead, so the signature would cover a canonical string that
+	// doesn't match what's persisted and the commit would show Unverified.
+	now := github.Timestamp{Time: time.Now()}
+	committer := &github.CommitAuthor{
+		Name:  github.String(os.Getenv("GITHUB_USERNAME")),
+		Email: github.String(os.Getenv("GITHUB_EMAIL")),
+		Date:  &now,
+	}
+
+	commit, _, err := gh.Git.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message:   github.String(message),
+		Tree:      tree,
+		Parents:   []*github.Commit{{SHA: github.String(parentSHA)}},
+		Author:    committer,
+		Committer: committer,
+	}, &github.CreateCommitOptions{Signer: messageSigner(signer)})
+	if err != nil {
+		return "", fmt.Errorf("create commit: %w", err)
+	}
+
+	return commit.GetSHA(), nil
+}
+
+// updateBranchRef points branch at sha, creating it from scratch if it
+// doesn't exist yet.
+func updateBranchRef(ctx context.Context, gh *github.Client, owner, repo, branch, sha string) error {
+	ref := &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	}
+
+	if _, _, err := gh.Git.CreateRef(ctx, owner, repo, ref); err != nil {
+		if _, _, err := gh.Git.UpdateRef(ctx, owner, repo, ref, true); err != nil {
+			return fmt.Errorf("update ref %s: %w", branch, err)
+		}
+	}
+
+	return nil
+}
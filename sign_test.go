@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// startGPGAgent launches the agent gpg's own key-generation otherwise tries
+// to auto-spawn. Started directly (not through gpg's auto-launch), its
+// stdout/stderr aren't attached to the test process, so Cmd.Wait doesn't
+// block once the daemonizing parent exits.
+func startGPGAgent(t *testing.T) {
+	t.Helper()
+
+	logFile, err := os.CreateTemp(t.TempDir(), "gpg-agent-log")
+	if err != nil {
+		t.Fatalf("create gpg-agent log file: %v", err)
+	}
+	defer logFile.Close()
+
+	// Stderr must be a real *os.File, not a pipe: gpg-agent --daemon forks
+	// and the parent exits once the background daemon is up, but the
+	// daemon keeps any inherited pipe's write end open, which would hang
+	// Cmd.Wait forever waiting for that pipe to reach EOF.
+	cmd := exec.Command("gpg-agent", "--daemon")
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start gpg-agent: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		log, _ := os.ReadFile(logFile.Name())
+		t.Fatalf("gpg-agent --daemon: %v\n%s", err, log)
+	}
+}
+
+// generateArmoredKey shells out to gpg to produce a real ASCII-armored
+// secret key, encrypted with passphrase when non-empty. x/crypto/openpgp
+// can only decrypt private keys, not encrypt them, so a real keypair is the
+// most faithful fixture for exercising loadSigner.
+func generateArmoredKey(t *testing.T, passphrase string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg binary not available")
+	}
+
+	// A short-lived, short-named directory: gpg-agent binds a unix socket
+	// under GNUPGHOME, and t.TempDir() embeds the (possibly long) test
+	// name, which can overflow the platform's socket path length limit.
+	gnupgHome, err := os.MkdirTemp("", "gnupghome")
+	if err != nil {
+		t.Fatalf("create GNUPGHOME: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(gnupgHome) })
+	if err := os.Chmod(gnupgHome, 0700); err != nil {
+		t.Fatalf("chmod GNUPGHOME: %v", err)
+	}
+	t.Setenv("GNUPGHOME", gnupgHome)
+
+	startGPGAgent(t)
+
+	genKey := exec.Command("gpg", "--batch", "--pinentry-mode", "loopback", "--passphrase", passphrase,
+		"--quick-generate-key", "tester <tester@example.com>", "default", "default", "never")
+	if out, err := genKey.CombinedOutput(); err != nil {
+		t.Fatalf("gpg --quick-generate-key: %v\n%s", err, out)
+	}
+
+	export := exec.Command("gpg", "--batch", "--pinentry-mode", "loopback", "--passphrase", passphrase,
+		"--armor", "--export-secret-keys", "tester@example.com")
+	out, err := export.Output()
+	if err != nil {
+		t.Fatalf("gpg --export-secret-keys: %v", err)
+	}
+
+	return string(out)
+}
+
+// emptyArmoredKeyRing returns a syntactically valid armored key block with no
+// key packets in it, exercising the "key ring has no entities" path.
+func emptyArmoredKeyRing(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatalf("armor encode: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close armor writer: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestLoadSigner(t *testing.T) {
+	tests := []struct {
+		name       string
+		armoredKey func(t *testing.T) string
+		passphrase string
+		wantErr    bool
+	}{
+		{
+			name:       "unencrypted key",
+			armoredKey: func(t *testing.T) string { return generateArmoredKey(t, "") },
+		},
+		{
+			name:       "passphrase-encrypted key decrypts with the right passphrase",
+			armoredKey: func(t *testing.T) string { return generateArmoredKey(t, "s3cret") },
+			passphrase: "s3cret",
+		},
+		{
+			name:       "passphrase-encrypted key fails to decrypt with the wrong passphrase",
+			armoredKey: func(t *testing.T) string { return generateArmoredKey(t, "s3cret") },
+			passphrase: "wrong",
+			wantErr:    true,
+		},
+		{
+			name:       "empty GPG_SECRET_KEY",
+			armoredKey: func(t *testing.T) string { return "" },
+			wantErr:    true,
+		},
+		{
+			name:       "key ring with no entities",
+			armoredKey: func(t *testing.T) string { return emptyArmoredKeyRing(t) },
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			armoredKey := tt.armoredKey(t)
+
+			t.Setenv("GPG_SECRET_KEY", armoredKey)
+			t.Setenv("GPG_PASSPHRASE", tt.passphrase)
+
+			signer, err := loadSigner()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("loadSigner(): expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadSigner(): unexpected error: %v", err)
+			}
+
+			if signer.PrivateKey == nil || signer.PrivateKey.Encrypted {
+				t.Fatalf("loadSigner(): returned signer with an undecrypted or missing private key")
+			}
+			for _, subkey := range signer.Subkeys {
+				if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+					t.Fatalf("loadSigner(): returned signer with an undecrypted subkey")
+				}
+			}
+		})
+	}
+}
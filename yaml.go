@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlPathSegment is one step of a dotted/bracketed path such as
+// "spec.template.spec.containers[0].image" or "images.api.tag".
+type yamlPathSegment struct {
+	key   string
+	index int // -1 when this segment addresses a map key, not a sequence index
+}
+
+// parseYAMLPath splits a YAMLPath-style expression into the segments
+// findYAMLNode walks.
+func parseYAMLPath(path string) ([]yamlPathSegment, error) {
+	var segments []yamlPathSegment
+
+	for _, part := range strings.Split(path, ".") {
+		rest := part
+		for {
+			open := strings.IndexByte(rest, '[')
+			if open == -1 {
+				break
+			}
+			close := strings.IndexByte(rest, ']')
+			if close == -1 || close < open {
+				return nil, fmt.Errorf("malformed path segment %q", part)
+			}
+
+			if open > 0 {
+				segments = append(segments, yamlPathSegment{key: rest[:open], index: -1})
+			}
+
+			idx, err := strconv.Atoi(rest[open+1 : close])
+			if err != nil {
+				return nil, fmt.Errorf("malformed index in %q: %w", part, err)
+			}
+			segments = append(segments, yamlPathSegment{index: idx})
+
+			rest = rest[close+1:]
+		}
+
+		if rest != "" {
+			segments = append(segments, yamlPathSegment{key: rest, index: -1})
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	return segments, nil
+}
+
+// findYAMLNode walks a decoded document down path and returns the node it
+// addresses.
+func findYAMLNode(doc *yaml.Node, path []yamlPathSegment) (*yaml.Node, error) {
+	node := doc
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, fmt.Errorf("empty document")
+		}
+		node = node.Content[0]
+	}
+
+	for _, seg := range path {
+		if seg.index >= 0 {
+			if node.Kind != yaml.SequenceNode || seg.index >= len(node.Content) {
+				return nil, fmt.Errorf("index %d out of range", seg.index)
+			}
+			node = node.Content[seg.index]
+			continue
+		}
+
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("expected a mapping at %q", seg.key)
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == seg.key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("key %q not found", seg.key)
+		}
+		node = next
+	}
+
+	return node, nil
+}
+
+// decodeYAMLDocuments reads every document out of a multi-document
+// (Kustomize/Helm style) YAML file.
+func decodeYAMLDocuments(data []byte) ([]*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []*yaml.Node
+	for {
+		doc := new(yaml.Node)
+		if err := decoder.Decode(doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode yaml: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// extractOldCommitHash reads the current value at yamlPath. Values written
+// with the "<master>_" prefix (e.g. "master_1a2b3c4d") have it stripped;
+// a bare hash with no prefix is returned as-is.
+func extractOldCommitHash(filePath, master, yamlPath string) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	segments, err := parseYAMLPath(yamlPath)
+	if err != nil {
+		return "", fmt.Errorf("parse yaml path %q: %w", yamlPath, err)
+	}
+
+	docs, err := decodeYAMLDocuments(data)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := master + "_"
+
+	var bareValue string
+	var bareFound bool
+
+	for _, doc := range docs {
+		node, err := findYAMLNode(doc, segments)
+		if err != nil || node.Kind != yaml.ScalarNode {
+			continue
+		}
+		if strings.HasPrefix(node.Value, prefix) {
+			return strings.TrimPrefix(node.Value, prefix), nil
+		}
+		if !bareFound {
+			bareValue, bareFound = node.Value, true
+		}
+	}
+
+	if bareFound {
+		return bareValue, nil
+	}
+
+	return "", fmt.Errorf("commit hash not found at %q in %s", yamlPath, filePath)
+}
+
+// renderUpdatedYAML returns filePath's contents with the value at yamlPath
+// bumped from oldCommitHash to newCommitHash, preserving every other key,
+// comment and formatting choice. A value written with the "<master>_"
+// prefix (e.g. "master_1a2b3c4d") stays prefixed; a bare hash stays bare.
+func renderUpdatedYAML(filePath, master, yamlPath, oldCommitHash, newCommitHash string) ([]byte, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	segments, err := parseYAMLPath(yamlPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse yaml path %q: %w", yamlPath, err)
+	}
+
+	docs, err := decodeYAMLDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixedOld := fmt.Sprintf("%s_%s", master, oldCommitHash)
+	prefixedNew := fmt.Sprintf("%s_%s", master, newCommitHash)
+
+	// Collect every scalar at yamlPath across all documents, then prefer a
+	// prefixed match over a bare one, the same precedence
+	// extractOldCommitHash uses, so the two agree on which document holds
+	// the value to bump when the path exists in more than one.
+	var prefixedNode, bareNode *yaml.Node
+
+	for _, doc := range docs {
+		node, err := findYAMLNode(doc, segments)
+		if err != nil || node.Kind != yaml.ScalarNode {
+			continue
+		}
+
+		switch node.Value {
+		case prefixedOld:
+			if prefixedNode == nil {
+				prefixedNode = node
+			}
+		case oldCommitHash:
+			if bareNode == nil {
+				bareNode = node
+			}
+		}
+	}
+
+	switch {
+	case prefixedNode != nil:
+		prefixedNode.Value = prefixedNew
+	case bareNode != nil:
+		bareNode.Value = newCommitHash
+	default:
+		return nil, fmt.Errorf("value %q (or bare %q) not found at %q in %s", prefixedOld, oldCommitHash, yamlPath, filePath)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("encode yaml: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("close yaml encoder: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// updateYamlFile rewrites filePath in place with the bumped value.
+func updateYamlFile(filePath, master, yamlTag, oldCommitHash, newCommitHash string) error {
+	output, err := renderUpdatedYAML(filePath, master, yamlTag, oldCommitHash, newCommitHash)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, output, 0644); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseYAMLPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []yamlPathSegment
+		wantErr bool
+	}{
+		{
+			name: "simple dotted path",
+			path: "images.api.tag",
+			want: []yamlPathSegment{
+				{key: "images", index: -1},
+				{key: "api", index: -1},
+				{key: "tag", index: -1},
+			},
+		},
+		{
+			name: "nested sequence index",
+			path: "spec.template.spec.containers[0].image",
+			want: []yamlPathSegment{
+				{key: "spec", index: -1},
+				{key: "template", index: -1},
+				{key: "spec", index: -1},
+				{key: "containers", index: -1},
+				{index: 0},
+				{key: "image", index: -1},
+			},
+		},
+		{
+			name:    "malformed index",
+			path:    "containers[x].image",
+			wantErr: true,
+		},
+		{
+			name:    "empty path",
+			path:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAMLPath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseYAMLPath(%q): expected error, got none", tt.path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseYAMLPath(%q): unexpected error: %v", tt.path, err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseYAMLPath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("parseYAMLPath(%q)[%d] = %+v, want %+v", tt.path, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExtractOldCommitHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		yamlPath string
+		want     string
+	}{
+		{
+			name:     "prefixed value",
+			contents: "images:\n  api:\n    tag: master_1a2b3c4d\n",
+			yamlPath: "images.api.tag",
+			want:     "1a2b3c4d",
+		},
+		{
+			name:     "bare value without master_ prefix",
+			contents: "images:\n  api:\n    tag: 1a2b3c4d\n",
+			yamlPath: "images.api.tag",
+			want:     "1a2b3c4d",
+		},
+		{
+			name:     "sequence index",
+			contents: "containers:\n  - image: master_deadbeef\n",
+			yamlPath: "containers[0].image",
+			want:     "deadbeef",
+		},
+		{
+			name:     "second document holds the prefixed value",
+			contents: "unrelated: true\n---\nimages:\n  api:\n    tag: master_cafef00d\n",
+			yamlPath: "images.api.tag",
+			want:     "cafef00d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "release.yaml")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatalf("write fixture: %v", err)
+			}
+
+			got, err := extractOldCommitHash(path, "master", tt.yamlPath)
+			if err != nil {
+				t.Fatalf("extractOldCommitHash: unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("extractOldCommitHash = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUpdatedYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name:     "prefixed value stays prefixed",
+			contents: "images:\n  api:\n    tag: master_1a2b3c4d\n",
+			want:     "images:\n  api:\n    tag: master_deadbeef\n",
+		},
+		{
+			name:     "bare value stays bare",
+			contents: "images:\n  api:\n    tag: 1a2b3c4d\n",
+			want:     "images:\n  api:\n    tag: deadbeef\n",
+		},
+		{
+			name:     "prefixed match wins over a bare match in an earlier document",
+			contents: "images:\n  api:\n    tag: 1a2b3c4d\n---\nimages:\n  api:\n    tag: master_1a2b3c4d\n",
+			want:     "images:\n  api:\n    tag: 1a2b3c4d\n---\nimages:\n  api:\n    tag: master_deadbeef\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "release.yaml")
+			if err := os.WriteFile(path, []byte(tt.contents), 0644); err != nil {
+				t.Fatalf("write fixture: %v", err)
+			}
+
+			got, err := renderUpdatedYAML(path, "master", "images.api.tag", "1a2b3c4d", "deadbeef")
+			if err != nil {
+				t.Fatalf("renderUpdatedYAML: unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("renderUpdatedYAML = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}